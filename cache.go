@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a small dedup store a Notifier can consult before sending (see
+// dingtalk's WithDedupCache) to skip re-posting a notification seen
+// recently, so a noisy alert source doesn't burn a provider's rate limit
+type Cache interface {
+	// Get reports whether key is present and not yet expired
+	Get(key string) bool
+	// Set inserts key, expiring it after ttl
+	Set(key string, ttl time.Duration) error
+}
+
+// LRUCache is an in-memory, size-bounded Cache with a per-entry TTL
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewLRUCache builds an in-memory Cache holding at most capacity entries,
+// evicting the least recently used one once full. capacity <= 0 means
+// unbounded.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get reports whether key is present and not yet expired, and refreshes its
+// recency if so
+func (c *LRUCache) Get(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return false
+	}
+
+	c.ll.MoveToFront(el)
+	return true
+}
+
+// Set inserts key, expiring it after ttl, evicting the least recently used
+// entry if the cache is at capacity
+func (c *LRUCache) Set(key string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).expiresAt = time.Now().Add(ttl)
+		c.ll.MoveToFront(el)
+		return nil
+	}
+
+	el := c.ll.PushFront(&lruEntry{key: key, expiresAt: time.Now().Add(ttl)})
+	c.items[key] = el
+
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		if oldest := c.ll.Back(); oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+
+	return nil
+}
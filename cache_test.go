@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEvictsLeastRecentlyUsedOverCapacity(t *testing.T) {
+	c := NewLRUCache(2)
+
+	if err := c.Set("a", time.Minute); err != nil {
+		t.Fatalf("set a: %v", err)
+	}
+	if err := c.Set("b", time.Minute); err != nil {
+		t.Fatalf("set b: %v", err)
+	}
+
+	// touch "a" so "b" becomes the least recently used entry
+	if !c.Get("a") {
+		t.Fatal("expected a to be present")
+	}
+
+	if err := c.Set("c", time.Minute); err != nil {
+		t.Fatalf("set c: %v", err)
+	}
+
+	if c.Get("b") {
+		t.Fatal("expected b to have been evicted as the least recently used entry")
+	}
+	if !c.Get("a") {
+		t.Fatal("expected a to still be present")
+	}
+	if !c.Get("c") {
+		t.Fatal("expected c to still be present")
+	}
+}
+
+func TestLRUCacheGetExpiresEntryPastTTL(t *testing.T) {
+	c := NewLRUCache(10)
+
+	if err := c.Set("key", 10*time.Millisecond); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if !c.Get("key") {
+		t.Fatal("expected key to be present before its TTL elapses")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if c.Get("key") {
+		t.Fatal("expected key to be gone once its TTL has elapsed")
+	}
+}
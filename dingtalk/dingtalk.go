@@ -0,0 +1,569 @@
+// Package dingtalk implements notify.Notifier for dding talk (钉钉) custom
+// robots: webhook signing, the text/markdown/link/actionCard/feedCard
+// payload shapes, client-side rate limiting and retry-with-backoff.
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ShyunnY/notify"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// dding talk api
+	apiUrl = "https://oapi.dingtalk.com/robot/send?access_token="
+
+	// MessageType
+	textType       = "text"
+	markdownType   = "markdown"
+	linkType       = "link"
+	actionCardType = "actionCard"
+	feedCardType   = "feedCard"
+	msgType        = "msgtype"
+
+	// AtType
+	at        = "at"
+	atMobiles = "atMobiles"
+	atUserIds = "atUserIds"
+	atAll     = "isAtAll"
+)
+
+// retryableErrCodes are dding talk error codes considered transient and
+// worth retrying: 130101 is the rate-limit quota error, -1 is system busy
+var retryableErrCodes = map[int]struct{}{
+	130101: {},
+	-1:     {},
+}
+
+// Robot is a dding talk custom robot. It implements notify.Notifier.
+type Robot struct {
+	AccessToken string
+	Secret      string
+	zlog        *zap.Logger
+
+	limiter      *rate.Limiter
+	blockOnLimit bool
+
+	httpClient  *http.Client
+	maxAttempts int
+	retryBase   time.Duration
+	retryCap    time.Duration
+
+	cache      notify.Cache
+	cacheTTL   time.Duration
+	cacheKeyFn func(notify.Message) string
+}
+
+// Option configures a Robot at construction time
+type Option func(*Robot)
+
+// WithRateLimit overrides the default client-side guardrail of
+// rate.Every(3*time.Second) with burst 20, which mirrors dding talk's
+// documented quota of 20 messages per minute per custom robot. Pass
+// rate.Inf to disable throttling entirely.
+func WithRateLimit(r rate.Limit, burst int) Option {
+	return func(robot *Robot) {
+		robot.limiter = rate.NewLimiter(r, burst)
+	}
+}
+
+// WithBlockOnLimit makes Send block until the limiter allows the request
+// instead of failing fast with ErrRateLimited when the bucket is empty
+func WithBlockOnLimit(block bool) Option {
+	return func(robot *Robot) {
+		robot.blockOnLimit = block
+	}
+}
+
+// ErrRateLimited is returned by Send when the client-side rate limiter
+// rejects a request and WithBlockOnLimit(true) was not set
+var ErrRateLimited = errors.New("dingtalk: rate limited by local guardrail")
+
+// WithRetry enables exponential backoff retries (full jitter) for network
+// errors, HTTP 5xx responses, and dding talk error codes considered
+// transient (130101 rate-limited, -1 system busy). The delay for attempt n
+// is min(cap, base*2^n) scaled by a random factor in [0,1).
+func WithRetry(maxAttempts int, base, cap time.Duration) Option {
+	return func(r *Robot) {
+		r.maxAttempts = maxAttempts
+		r.retryBase = base
+		r.retryCap = cap
+	}
+}
+
+// WithDedupCache fronts Send with a dedup window: before posting, it
+// computes keyFn(msg) (pass nil to use the default, which hashes the
+// translated msgtype+content payload with SHA-256, excluding the at block
+// and timestamp) and skips the HTTP call if the key is already present in
+// c, logging at debug level. The key is inserted with ttl only after a
+// successful send.
+func WithDedupCache(c notify.Cache, ttl time.Duration, keyFn func(notify.Message) string) Option {
+	return func(r *Robot) {
+		r.cache = c
+		r.cacheTTL = ttl
+		r.cacheKeyFn = keyFn
+	}
+}
+
+// NewRobot 新建机器人
+func NewRobot(accessToken, secret string, opts ...Option) *Robot {
+
+	prod, err := logInit()
+	if err != nil {
+		log.Fatalln("log init error: ", err)
+	}
+
+	r := &Robot{
+		AccessToken: accessToken,
+		Secret:      secret,
+		zlog:        prod,
+		limiter:     rate.NewLimiter(rate.Every(3*time.Second), 20),
+		httpClient:  &http.Client{},
+		maxAttempts: 1,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func logInit() (*zap.Logger, error) {
+	encConfig := zap.NewProductionEncoderConfig()
+	encConfig.EncodeTime = zapcore.RFC3339TimeEncoder
+	config := zap.NewProductionConfig()
+	config.EncoderConfig = encConfig
+	prod, err := config.Build()
+	return prod, err
+}
+
+// BuildMsgAndSend 构建信息
+func (r *Robot) BuildMsgAndSend(msg notify.Message, opts ...notify.AtOption) error {
+	return r.Send(context.Background(), msg, opts...)
+}
+
+// BuildMsgAndSendContext 构建信息并发送，支持 context 超时/取消以及 WithRetry 配置的重试
+func (r *Robot) BuildMsgAndSendContext(ctx context.Context, msg notify.Message, opts ...notify.AtOption) error {
+	return r.Send(ctx, msg, opts...)
+}
+
+// SendResult carries the outcome of a single BuildMsgAndSendResult call:
+// the decoded Response, the raw body it was decoded from, and the HTTP
+// status of the attempt that produced it.
+type SendResult struct {
+	Response   Response
+	Body       []byte
+	HTTPStatus int
+}
+
+// ErrMalformedResponse wraps a dding talk response body that failed to
+// unmarshal into Response, instead of silently zeroing out ErrCode
+type ErrMalformedResponse struct {
+	Body []byte
+	Err  error
+}
+
+func (e *ErrMalformedResponse) Error() string {
+	return fmt.Sprintf("dingtalk: malformed response body (%q): %v", e.Body, e.Err)
+}
+
+func (e *ErrMalformedResponse) Unwrap() error {
+	return e.Err
+}
+
+// ErrServerError indicates dding talk responded with an HTTP 5xx status
+// that retries were exhausted against, regardless of what its body decoded
+// to (a 5xx with an empty or zero-value body is still a failure)
+type ErrServerError struct {
+	HTTPStatus int
+}
+
+func (e *ErrServerError) Error() string {
+	return fmt.Sprintf("dingtalk: server error, http status %d", e.HTTPStatus)
+}
+
+// Send implements notify.Notifier, translating msg into dding talk's native
+// payload shape before posting it to the robot webhook
+func (r *Robot) Send(ctx context.Context, msg notify.Message, opts ...notify.AtOption) error {
+	_, err := r.sendAndResult(ctx, msg, opts...)
+	return err
+}
+
+// BuildMsgAndSendResult is like BuildMsgAndSend but returns a *SendResult
+// carrying the raw response body and HTTP status alongside the decoded
+// Response, which endpoints like actionCard's extra fields or a failed
+// send are otherwise unable to surface to the caller.
+func (r *Robot) BuildMsgAndSendResult(msg notify.Message, opts ...notify.AtOption) (*SendResult, error) {
+	return r.sendAndResult(context.Background(), msg, opts...)
+}
+
+func (r *Robot) sendAndResult(ctx context.Context, msg notify.Message, opts ...notify.AtOption) (*SendResult, error) {
+
+	payload, err := toPayload(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the dedup cache before consulting the rate limiter, so a
+	// duplicate that gets skipped never burns a token off the budget
+	var dedupKey string
+	if r.cache != nil {
+		dedupKey = r.dedupKey(msg)
+		if r.cache.Get(dedupKey) {
+			r.zlog.Debug("dding talk send skipped, duplicate within dedup window", zap.String("key", dedupKey))
+			return &SendResult{}, nil
+		}
+	}
+
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// dding talk rejects actionCard/feedCard payloads that carry an "at"
+	// block, so skip it for those message types instead of poisoning them
+	if msg.Kind != notify.KindActionCard && msg.Kind != notify.KindFeedCard {
+		people := new(notify.AtPeople)
+		for _, opt := range opts {
+			opt(people)
+		}
+		payload[at] = serializeAt(people)
+	}
+
+	reqData, err := sonic.Marshal(payload)
+	if err != nil {
+		r.zlog.Error("sonic serialization failed for data", zap.Error(err))
+		return nil, err
+	}
+
+	result, err := r.doWithRetry(ctx, reqData)
+	if err == nil && r.cache != nil {
+		if cerr := r.cache.Set(dedupKey, r.cacheTTL); cerr != nil {
+			r.zlog.Warn("failed to record dedup cache entry", zap.String("key", dedupKey), zap.Error(cerr))
+		}
+	}
+
+	return result, err
+}
+
+// dedupKey computes the dedup cache key for msg, using the configured
+// cacheKeyFn if one was given to WithDedupCache or the default otherwise
+func (r *Robot) dedupKey(msg notify.Message) string {
+	if r.cacheKeyFn != nil {
+		return r.cacheKeyFn(msg)
+	}
+	return defaultDedupKey(msg)
+}
+
+// defaultDedupKey hashes the translated msgtype+content payload with
+// SHA-256, excluding the at block and timestamp (neither of which is part
+// of toPayload's output)
+func defaultDedupKey(msg notify.Message) string {
+	payload, err := toPayload(msg)
+	if err != nil {
+		return ""
+	}
+
+	data, err := sonic.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// doWithRetry posts reqData to the robot webhook, retrying transient
+// failures per WithRetry before giving up
+func (r *Robot) doWithRetry(ctx context.Context, reqData []byte) (*SendResult, error) {
+
+	url := r.buildQuery()
+
+	attempts := r.maxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var (
+		respData   []byte
+		statusCode int
+		result     *Response
+		err        error
+	)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		result = nil
+		respData, statusCode, err = r.doRequest(ctx, url, reqData)
+
+		retryable := false
+		if err != nil {
+			retryable = true
+		} else {
+			result = new(Response)
+			if uerr := sonic.Unmarshal(respData, result); uerr != nil {
+				err = &ErrMalformedResponse{Body: respData, Err: uerr}
+				retryable = statusCode >= http.StatusInternalServerError
+			} else {
+				retryable = statusCode >= http.StatusInternalServerError || isRetryableCode(result.ErrCode)
+			}
+		}
+
+		if !retryable || attempt == attempts-1 {
+			break
+		}
+
+		sleep := backoffDuration(r.retryBase, r.retryCap, attempt)
+		r.zlog.Warn("dding talk api call failed, retrying",
+			zap.Int("attempt", attempt+1), zap.Duration("backoff", sleep), zap.Error(err))
+
+		timer := time.NewTimer(sleep)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return &SendResult{Body: respData, HTTPStatus: statusCode}, ctx.Err()
+		}
+	}
+
+	sendResult := &SendResult{Body: respData, HTTPStatus: statusCode}
+	if result != nil {
+		sendResult.Response = *result
+	}
+
+	if err != nil {
+		r.zlog.Error("dding talk api call failed", zap.Error(err))
+		return sendResult, err
+	}
+
+	// a 5xx is a failure even when the body happens to unmarshal cleanly
+	// into a zero-value Response (e.g. an upstream gateway error page)
+	if statusCode >= http.StatusInternalServerError {
+		err := &ErrServerError{HTTPStatus: statusCode}
+		r.zlog.Error("dding talk api call failed", zap.Error(err))
+		return sendResult, err
+	}
+
+	if result.ErrCode != 0 {
+		r.zlog.Error(result.Error())
+		return sendResult, result
+	}
+
+	r.zlog.Info("message was successfully send to dding talk")
+	return sendResult, nil
+}
+
+// doRequest performs a single attempt against dding talk, draining and
+// closing the response body before returning so the connection can be reused
+func (r *Robot) doRequest(ctx context.Context, url string, reqData []byte) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	// 获取钉钉响应
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		r.zlog.Error("read dding talk response error", zap.Error(err))
+		return nil, resp.StatusCode, err
+	}
+
+	return respData, resp.StatusCode, nil
+}
+
+// isRetryableCode reports whether a dding talk error code is transient
+func isRetryableCode(code int) bool {
+	_, ok := retryableErrCodes[code]
+	return ok
+}
+
+// backoffDuration computes an exponential backoff delay with full jitter:
+// sleep = min(cap, base*2^attempt) * rand.Float64(). A non-positive base
+// means no delay between attempts.
+func backoffDuration(base, cap time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	d := base << attempt
+	if d <= 0 || (cap > 0 && d > cap) {
+		d = cap
+	}
+
+	return time.Duration(rand.Float64() * float64(d))
+}
+
+// wait consults the client-side rate limiter before a message is posted,
+// either blocking until a token is available or failing fast with
+// ErrRateLimited depending on WithBlockOnLimit
+func (r *Robot) wait(ctx context.Context) error {
+	if r.limiter.Allow() {
+		return nil
+	}
+
+	if !r.blockOnLimit {
+		r.zlog.Warn("dding talk send throttled by local rate limiter")
+		return ErrRateLimited
+	}
+
+	r.zlog.Warn("dding talk send blocked by local rate limiter, waiting for a free token")
+	return r.limiter.Wait(ctx)
+}
+
+func (r *Robot) buildQuery() string {
+	timestamp := time.Now().UnixMilli()
+	signature := fmt.Sprintf("%d\n%s", timestamp, r.Secret)
+
+	// hmac encrypt
+	hash := hmac.New(sha256.New, []byte(r.Secret))
+	hash.Write([]byte(signature))
+	sign := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+
+	// concat query param
+	webhook := apiUrl + r.AccessToken
+	url := fmt.Sprintf("%s&timestamp=%d&sign=%s", webhook, timestamp, sign)
+	return url
+}
+
+// toPayload translates a notify.Message into dding talk's native payload
+func toPayload(msg notify.Message) (map[string]interface{}, error) {
+	switch msg.Kind {
+	case notify.KindText:
+		if msg.Text == nil {
+			return nil, fmt.Errorf("dingtalk: message kind %q has a nil Text", msg.Kind)
+		}
+		return map[string]interface{}{
+			msgType:  textType,
+			textType: map[string]string{"content": msg.Text.Content},
+		}, nil
+
+	case notify.KindMarkdown:
+		if msg.Markdown == nil {
+			return nil, fmt.Errorf("dingtalk: message kind %q has a nil Markdown", msg.Kind)
+		}
+		return map[string]interface{}{
+			msgType: markdownType,
+			markdownType: map[string]string{
+				"title": msg.Markdown.Title,
+				"text":  msg.Markdown.Text,
+			},
+		}, nil
+
+	case notify.KindLink:
+		if msg.Link == nil {
+			return nil, fmt.Errorf("dingtalk: message kind %q has a nil Link", msg.Kind)
+		}
+		return map[string]interface{}{
+			msgType: linkType,
+			linkType: map[string]string{
+				"title":      msg.Link.Title,
+				"text":       msg.Link.Text,
+				"messageUrl": msg.Link.MessageURL,
+				"picUrl":     msg.Link.PicURL,
+			},
+		}, nil
+
+	case notify.KindActionCard:
+		if msg.ActionCard == nil {
+			return nil, fmt.Errorf("dingtalk: message kind %q has a nil ActionCard", msg.Kind)
+		}
+		card := map[string]interface{}{
+			"title": msg.ActionCard.Title,
+			"text":  msg.ActionCard.Text,
+		}
+		if msg.ActionCard.BtnOrientation != "" {
+			card["btnOrientation"] = msg.ActionCard.BtnOrientation
+		}
+
+		switch btns := msg.ActionCard.Btns; len(btns) {
+		case 0:
+		case 1:
+			card["singleTitle"] = btns[0].Title
+			card["singleURL"] = btns[0].ActionURL
+		default:
+			btnList := make([]map[string]string, 0, len(btns))
+			for _, btn := range btns {
+				btnList = append(btnList, map[string]string{
+					"title":     btn.Title,
+					"actionURL": btn.ActionURL,
+				})
+			}
+			card["btns"] = btnList
+		}
+
+		return map[string]interface{}{msgType: actionCardType, actionCardType: card}, nil
+
+	case notify.KindFeedCard:
+		if msg.FeedCard == nil {
+			return nil, fmt.Errorf("dingtalk: message kind %q has a nil FeedCard", msg.Kind)
+		}
+		linkList := make([]map[string]string, 0, len(msg.FeedCard.Links))
+		for _, link := range msg.FeedCard.Links {
+			linkList = append(linkList, map[string]string{
+				"title":      link.Title,
+				"messageURL": link.MessageURL,
+				"picURL":     link.PicURL,
+			})
+		}
+		return map[string]interface{}{
+			msgType:      feedCardType,
+			feedCardType: map[string]interface{}{"links": linkList},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("dingtalk: unsupported message kind %q", msg.Kind)
+	}
+}
+
+// serializeAt converts the common notify.AtPeople into dding talk's at block
+func serializeAt(p *notify.AtPeople) map[string]interface{} {
+	ret := map[string]interface{}{}
+
+	if len(p.AtMobiles) > 0 {
+		ret[atMobiles] = p.AtMobiles
+	}
+
+	if len(p.AtUserIds) > 0 {
+		ret[atUserIds] = p.AtUserIds
+	}
+
+	if p.IsAtAll {
+		ret[atAll] = p.IsAtAll
+	}
+
+	return ret
+}
+
+// Response is the JSON body dding talk returns for every robot send call
+type Response struct {
+	ErrCode int
+	ErrMsg  string
+}
+
+func (r Response) Error() string {
+	return fmt.Sprintf("dding talk response info: errcode=%d,errmsg=%s", r.ErrCode, r.ErrMsg)
+}
@@ -0,0 +1,316 @@
+package dingtalk
+
+import (
+	"context"
+	"errors"
+	"github.com/ShyunnY/notify"
+	"golang.org/x/time/rate"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestSendRetryExhaustedOnServerError(t *testing.T) {
+	r := NewRobot("token", "secret", WithRetry(2, time.Millisecond, time.Millisecond))
+
+	calls := 0
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		// a 5xx with a body that unmarshals cleanly into a zero-value
+		// Response must still be treated as a failure, not a silent success
+		return jsonResponse(http.StatusInternalServerError, "{}"), nil
+	})}
+
+	err := r.Send(context.Background(), notify.TextType("hi"))
+	if err == nil {
+		t.Fatal("expected an error after retries were exhausted against a 5xx response")
+	}
+
+	var serverErr *ErrServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("expected *ErrServerError, got %T: %v", err, err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestSendDedupSkipsRateLimiter(t *testing.T) {
+	r := NewRobot("token", "secret",
+		WithRateLimit(rate.Every(time.Hour), 1),
+		WithBlockOnLimit(false),
+		WithDedupCache(notify.NewLRUCache(10), time.Minute, nil),
+	)
+
+	calls := 0
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusOK, `{"errcode":0,"errmsg":"ok"}`), nil
+	})}
+
+	msg := notify.TextType("duplicate alert")
+
+	if err := r.Send(context.Background(), msg); err != nil {
+		t.Fatalf("first send: unexpected error: %v", err)
+	}
+
+	// the single rate-limit token was spent on the first send above; if the
+	// dedup check ran after the limiter instead of before it, this call
+	// would fail with ErrRateLimited instead of being skipped as a no-op
+	if err := r.Send(context.Background(), msg); err != nil {
+		t.Fatalf("second (duplicate) send: unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the HTTP call to fire once, got %d", calls)
+	}
+}
+
+func TestToPayloadMessageKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  notify.Message
+		want map[string]interface{}
+	}{
+		{
+			name: "text",
+			msg:  notify.TextType("hello"),
+			want: map[string]interface{}{
+				msgType:  textType,
+				textType: map[string]string{"content": "hello"},
+			},
+		},
+		{
+			name: "markdown",
+			msg:  notify.MarkDownType("title", "body"),
+			want: map[string]interface{}{
+				msgType:      markdownType,
+				markdownType: map[string]string{"title": "title", "text": "body"},
+			},
+		},
+		{
+			name: "link",
+			msg:  notify.LinkType("title", "text", "https://example.com/msg", "https://example.com/pic"),
+			want: map[string]interface{}{
+				msgType: linkType,
+				linkType: map[string]string{
+					"title":      "title",
+					"text":       "text",
+					"messageUrl": "https://example.com/msg",
+					"picUrl":     "https://example.com/pic",
+				},
+			},
+		},
+		{
+			name: "actionCard single button",
+			msg: notify.ActionCardType("title", "text", "0",
+				notify.ActionButton{Title: "open", ActionURL: "https://example.com/open"}),
+			want: map[string]interface{}{
+				msgType: actionCardType,
+				actionCardType: map[string]interface{}{
+					"title":          "title",
+					"text":           "text",
+					"btnOrientation": "0",
+					"singleTitle":    "open",
+					"singleURL":      "https://example.com/open",
+				},
+			},
+		},
+		{
+			name: "actionCard multiple buttons",
+			msg: notify.ActionCardType("title", "text", "",
+				notify.ActionButton{Title: "a", ActionURL: "https://example.com/a"},
+				notify.ActionButton{Title: "b", ActionURL: "https://example.com/b"}),
+			want: map[string]interface{}{
+				msgType: actionCardType,
+				actionCardType: map[string]interface{}{
+					"title": "title",
+					"text":  "text",
+					"btns": []map[string]string{
+						{"title": "a", "actionURL": "https://example.com/a"},
+						{"title": "b", "actionURL": "https://example.com/b"},
+					},
+				},
+			},
+		},
+		{
+			name: "feedCard",
+			msg: notify.FeedCardType(
+				notify.FeedLink{Title: "one", MessageURL: "https://example.com/1", PicURL: "https://example.com/1.png"},
+			),
+			want: map[string]interface{}{
+				msgType: feedCardType,
+				feedCardType: map[string]interface{}{
+					"links": []map[string]string{
+						{"title": "one", "messageURL": "https://example.com/1", "picURL": "https://example.com/1.png"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toPayload(tt.msg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("toPayload mismatch\ngot:  %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendRateLimitedFailsFast(t *testing.T) {
+	r := NewRobot("token", "secret", WithRateLimit(rate.Every(time.Hour), 1))
+
+	calls := 0
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusOK, `{"errcode":0,"errmsg":"ok"}`), nil
+	})}
+
+	msg := notify.TextType("hi")
+
+	if err := r.Send(context.Background(), msg); err != nil {
+		t.Fatalf("first send: unexpected error: %v", err)
+	}
+
+	// the single token was spent above, so this call must fail fast instead
+	// of hitting the network
+	err := r.Send(context.Background(), msg)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected only the first call to reach the network, got %d", calls)
+	}
+}
+
+func TestSendBlocksOnLimitUntilTokenFrees(t *testing.T) {
+	r := NewRobot("token", "secret",
+		WithRateLimit(rate.Every(20*time.Millisecond), 1),
+		WithBlockOnLimit(true),
+	)
+
+	calls := 0
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return jsonResponse(http.StatusOK, `{"errcode":0,"errmsg":"ok"}`), nil
+	})}
+
+	msg := notify.TextType("hi")
+
+	if err := r.Send(context.Background(), msg); err != nil {
+		t.Fatalf("first send: unexpected error: %v", err)
+	}
+
+	// the bucket is now empty; this call must block until the limiter
+	// refills a token rather than failing fast with ErrRateLimited
+	start := time.Now()
+	if err := r.Send(context.Background(), msg); err != nil {
+		t.Fatalf("second send: unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected Send to block waiting for a token, returned after %v", elapsed)
+	}
+	if calls != 2 {
+		t.Fatalf("expected both calls to reach the network, got %d", calls)
+	}
+}
+
+func TestBuildMsgAndSendResultSuccess(t *testing.T) {
+	r := NewRobot("token", "secret")
+
+	body := `{"errcode":0,"errmsg":"ok"}`
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, body), nil
+	})}
+
+	result, err := r.BuildMsgAndSendResult(notify.TextType("hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.HTTPStatus != http.StatusOK {
+		t.Fatalf("expected HTTPStatus %d, got %d", http.StatusOK, result.HTTPStatus)
+	}
+	if string(result.Body) != body {
+		t.Fatalf("expected Body %q, got %q", body, result.Body)
+	}
+	if result.Response.ErrCode != 0 {
+		t.Fatalf("expected ErrCode 0, got %d", result.Response.ErrCode)
+	}
+}
+
+func TestBuildMsgAndSendResultMalformedBody(t *testing.T) {
+	r := NewRobot("token", "secret")
+
+	r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, "not json"), nil
+	})}
+
+	result, err := r.BuildMsgAndSendResult(notify.TextType("hi"))
+	if err == nil {
+		t.Fatal("expected an error for a malformed response body")
+	}
+
+	var malformedErr *ErrMalformedResponse
+	if !errors.As(err, &malformedErr) {
+		t.Fatalf("expected *ErrMalformedResponse, got %T: %v", err, err)
+	}
+	if errors.Unwrap(malformedErr) == nil {
+		t.Fatal("expected Unwrap to return the underlying unmarshal error")
+	}
+	if result == nil || string(result.Body) != "not json" {
+		t.Fatalf("expected result.Body to carry the raw body, got %v", result)
+	}
+}
+
+func TestSendStripsAtForActionCardAndFeedCard(t *testing.T) {
+	cases := []struct {
+		name string
+		msg  notify.Message
+	}{
+		{"actionCard", notify.ActionCardType("title", "text", "0", notify.ActionButton{Title: "a", ActionURL: "https://example.com"})},
+		{"feedCard", notify.FeedCardType(notify.FeedLink{Title: "one", MessageURL: "https://example.com"})},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRobot("token", "secret")
+
+			var body []byte
+			r.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, _ = io.ReadAll(req.Body)
+				return jsonResponse(http.StatusOK, `{"errcode":0,"errmsg":"ok"}`), nil
+			})}
+
+			err := r.Send(context.Background(), tc.msg, notify.WithAtAll(), notify.WithAtUserIds("u1"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Contains(string(body), `"`+at+`"`) {
+				t.Fatalf("expected no %q block in payload, got: %s", at, body)
+			}
+		})
+	}
+}
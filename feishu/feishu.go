@@ -0,0 +1,184 @@
+// Package feishu implements notify.Notifier for Feishu/Lark custom bots
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/ShyunnY/notify"
+	"github.com/bytedance/sonic"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Bot is a Feishu/Lark custom bot. It implements notify.Notifier.
+type Bot struct {
+	WebhookURL string
+	Secret     string
+
+	httpClient *http.Client
+}
+
+// NewBot builds a Feishu bot posting to webhookURL. secret is optional and
+// only needed when the bot has signature verification enabled.
+func NewBot(webhookURL, secret string) *Bot {
+	return &Bot{
+		WebhookURL: webhookURL,
+		Secret:     secret,
+		httpClient: &http.Client{},
+	}
+}
+
+// Send implements notify.Notifier, translating msg into Feishu's native
+// payload shape before posting it to the bot webhook. Feishu has no
+// dedicated actionCard/feedCard concept, so both are flattened to text.
+func (b *Bot) Send(ctx context.Context, msg notify.Message, opts ...notify.AtOption) error {
+
+	people := new(notify.AtPeople)
+	for _, opt := range opts {
+		opt(people)
+	}
+
+	payload := toPayload(msg, people)
+
+	if b.Secret != "" {
+		timestamp := time.Now().Unix()
+		sign, err := signature(timestamp, b.Secret)
+		if err != nil {
+			return err
+		}
+		payload["timestamp"] = timestamp
+		payload["sign"] = sign
+	}
+
+	reqData, err := sonic.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.WebhookURL, bytes.NewReader(reqData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	result := new(Response)
+	if err := sonic.Unmarshal(respData, result); err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return result
+	}
+
+	return nil
+}
+
+// signature computes Feishu's HMAC-SHA256 webhook signature
+func signature(timestamp int64, secret string) (string, error) {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+
+	hash := hmac.New(sha256.New, []byte(stringToSign))
+	if _, err := hash.Write([]byte{}); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// toPayload translates a notify.Message into Feishu's native payload.
+// Mentions are rendered as <at user_id="...">text</at> tags inside the
+// text content, following Feishu's own convention.
+func toPayload(msg notify.Message, people *notify.AtPeople) map[string]interface{} {
+	switch msg.Kind {
+	case notify.KindMarkdown:
+		return map[string]interface{}{
+			"msg_type": "post",
+			"content": map[string]interface{}{
+				"post": map[string]interface{}{
+					"zh_cn": map[string]interface{}{
+						"title": msg.Markdown.Title,
+						"content": [][]map[string]string{
+							{{"tag": "text", "text": msg.Markdown.Text}},
+						},
+					},
+				},
+			},
+		}
+
+	case notify.KindLink:
+		return map[string]interface{}{
+			"msg_type": "text",
+			"content": map[string]string{
+				"text": fmt.Sprintf("%s\n%s\n%s", msg.Link.Title, msg.Link.Text, msg.Link.MessageURL),
+			},
+		}
+
+	case notify.KindActionCard:
+		return map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": fmt.Sprintf("%s\n%s%s", msg.ActionCard.Title, msg.ActionCard.Text, mentionSuffix(people))},
+		}
+
+	case notify.KindFeedCard:
+		lines := make([]string, 0, len(msg.FeedCard.Links))
+		for _, link := range msg.FeedCard.Links {
+			lines = append(lines, fmt.Sprintf("%s %s", link.Title, link.MessageURL))
+		}
+		return map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": strings.Join(lines, "\n")},
+		}
+
+	default: // notify.KindText and anything unrecognized fall back to plain text
+		content := ""
+		if msg.Text != nil {
+			content = msg.Text.Content
+		}
+		return map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": content + mentionSuffix(people)},
+		}
+	}
+}
+
+// mentionSuffix renders the common notify.AtPeople as Feishu <at> tags
+func mentionSuffix(people *notify.AtPeople) string {
+	if people.IsAtAll {
+		return " <at user_id=\"all\">所有人</at>"
+	}
+
+	var b strings.Builder
+	for _, id := range people.AtUserIds {
+		b.WriteString(fmt.Sprintf(" <at user_id=\"%s\"></at>", id))
+	}
+	return b.String()
+}
+
+// Response is the JSON body Feishu returns for every bot send call
+type Response struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (r Response) Error() string {
+	return fmt.Sprintf("feishu response info: code=%d,msg=%s", r.Code, r.Msg)
+}
@@ -0,0 +1,102 @@
+package feishu
+
+import (
+	"github.com/ShyunnY/notify"
+	"reflect"
+	"testing"
+)
+
+func TestToPayloadMessageKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    notify.Message
+		people *notify.AtPeople
+		want   map[string]interface{}
+	}{
+		{
+			name:   "text",
+			msg:    notify.TextType("hello"),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msg_type": "text",
+				"content":  map[string]string{"text": "hello"},
+			},
+		},
+		{
+			name:   "text with at all",
+			msg:    notify.TextType("hello"),
+			people: &notify.AtPeople{IsAtAll: true},
+			want: map[string]interface{}{
+				"msg_type": "text",
+				"content":  map[string]string{"text": "hello <at user_id=\"all\">所有人</at>"},
+			},
+		},
+		{
+			name:   "markdown",
+			msg:    notify.MarkDownType("title", "body"),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msg_type": "post",
+				"content": map[string]interface{}{
+					"post": map[string]interface{}{
+						"zh_cn": map[string]interface{}{
+							"title": "title",
+							"content": [][]map[string]string{
+								{{"tag": "text", "text": "body"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "link",
+			msg:    notify.LinkType("title", "text", "https://example.com", "https://example.com/pic"),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msg_type": "text",
+				"content":  map[string]string{"text": "title\ntext\nhttps://example.com"},
+			},
+		},
+		{
+			name:   "feedCard",
+			msg:    notify.FeedCardType(notify.FeedLink{Title: "one", MessageURL: "https://example.com/1"}),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msg_type": "text",
+				"content":  map[string]string{"text": "one https://example.com/1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toPayload(tt.msg, tt.people)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("toPayload mismatch\ngot:  %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureIsDeterministicForSameInput(t *testing.T) {
+	sign1, err := signature(1700000000, "my-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sign2, err := signature(1700000000, "my-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sign1 != sign2 {
+		t.Fatalf("expected the same timestamp+secret to produce the same signature, got %q and %q", sign1, sign2)
+	}
+
+	sign3, err := signature(1700000001, "my-secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sign1 == sign3 {
+		t.Fatal("expected a different timestamp to produce a different signature")
+	}
+}
@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MsgKind identifies which field of Message is populated
+type MsgKind string
+
+const (
+	KindText       MsgKind = "text"
+	KindMarkdown   MsgKind = "markdown"
+	KindLink       MsgKind = "link"
+	KindActionCard MsgKind = "actionCard"
+	KindFeedCard   MsgKind = "feedCard"
+)
+
+// TextMessage is a plain text notification
+type TextMessage struct {
+	Content string
+}
+
+// MarkdownMessage is a notification rendered from markdown
+type MarkdownMessage struct {
+	Title string
+	Text  string
+}
+
+// LinkMessage is a notification with a thumbnail and a tap-through link
+type LinkMessage struct {
+	Title      string
+	Text       string
+	MessageURL string
+	PicURL     string
+}
+
+// ActionButton is a single button rendered inside an ActionCardMessage
+type ActionButton struct {
+	Title     string
+	ActionURL string
+}
+
+// ActionCardMessage is a card with one or more action buttons
+type ActionCardMessage struct {
+	Title          string
+	Text           string
+	BtnOrientation string
+	Btns           []ActionButton
+}
+
+// FeedLink is a single entry rendered inside a FeedCardMessage
+type FeedLink struct {
+	Title      string
+	MessageURL string
+	PicURL     string
+}
+
+// FeedCardMessage is a feed of linked entries
+type FeedCardMessage struct {
+	Links []FeedLink
+}
+
+// Message is the provider-agnostic notification payload passed to a
+// Notifier. Kind identifies which of the typed fields is populated; build
+// one with TextType, MarkDownType, LinkType, ActionCardType or FeedCardType
+// rather than constructing it directly.
+type Message struct {
+	Kind       MsgKind
+	Text       *TextMessage
+	Markdown   *MarkdownMessage
+	Link       *LinkMessage
+	ActionCard *ActionCardMessage
+	FeedCard   *FeedCardMessage
+}
+
+// TextType builds a plain text Message
+func TextType(content string) Message {
+	return Message{Kind: KindText, Text: &TextMessage{Content: content}}
+}
+
+// MarkDownType builds a markdown Message
+func MarkDownType(title, text string) Message {
+	return Message{Kind: KindMarkdown, Markdown: &MarkdownMessage{Title: title, Text: text}}
+}
+
+// LinkType builds a Message with a thumbnail and a tap-through link
+func LinkType(title, text, messageUrl, picUrl string) Message {
+	return Message{Kind: KindLink, Link: &LinkMessage{
+		Title: title, Text: text, MessageURL: messageUrl, PicURL: picUrl,
+	}}
+}
+
+// ActionCardType builds a Message with one or more action buttons. Providers
+// that only support a single button (e.g. wecom's textcard) use the first
+// entry of btns when more than one is given.
+func ActionCardType(title, text, btnOrientation string, btns ...ActionButton) Message {
+	return Message{Kind: KindActionCard, ActionCard: &ActionCardMessage{
+		Title: title, Text: text, BtnOrientation: btnOrientation, Btns: btns,
+	}}
+}
+
+// FeedCardType builds a Message listing one or more linked entries
+func FeedCardType(links ...FeedLink) Message {
+	return Message{Kind: KindFeedCard, FeedCard: &FeedCardMessage{Links: links}}
+}
+
+// AtPeople describes who a notification should mention. Providers that have
+// no concept of mentions (e.g. Slack incoming webhooks) may ignore it.
+type AtPeople struct {
+	AtMobiles []string
+	AtUserIds []string
+	IsAtAll   bool
+}
+
+type AtOption func(*AtPeople)
+
+func WithAtMobiles(mobiles ...string) AtOption {
+	return func(p *AtPeople) {
+		p.AtMobiles = mobiles
+	}
+}
+
+func WithAtUserIds(userIds ...string) AtOption {
+	return func(p *AtPeople) {
+		p.AtUserIds = userIds
+	}
+}
+
+func WithAtAll() AtOption {
+	return func(p *AtPeople) {
+		p.IsAtAll = true
+	}
+}
+
+// Notifier is implemented by each provider-specific bot (dingtalk, feishu,
+// wecom, slack, ...), translating the common Message into its native
+// payload and posting it.
+type Notifier interface {
+	Send(ctx context.Context, msg Message, opts ...AtOption) error
+}
+
+// MultiNotifier fans a single Send call out to several Notifiers in
+// parallel, letting callers mirror an alert to multiple channels at once
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMultiNotifier builds a MultiNotifier that sends to every notifier given
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+// Send dispatches msg to every underlying Notifier concurrently and joins
+// any per-provider errors together
+func (m *MultiNotifier) Send(ctx context.Context, msg Message, opts ...AtOption) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	wg.Add(len(m.notifiers))
+	for _, n := range m.notifiers {
+		go func(n Notifier) {
+			defer wg.Done()
+			if err := n.Send(ctx, msg, opts...); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeNotifier struct {
+	err   error
+	calls int32
+}
+
+func (f *fakeNotifier) Send(ctx context.Context, msg Message, opts ...AtOption) error {
+	atomic.AddInt32(&f.calls, 1)
+	return f.err
+}
+
+func TestMultiNotifierSendInvokesAllAndJoinsErrors(t *testing.T) {
+	errA := errors.New("provider a failed")
+	errB := errors.New("provider b failed")
+
+	a := &fakeNotifier{err: errA}
+	b := &fakeNotifier{err: errB}
+	c := &fakeNotifier{}
+
+	m := NewMultiNotifier(a, b, c)
+
+	err := m.Send(context.Background(), TextType("hi"))
+	if err == nil {
+		t.Fatal("expected a joined error from the two failing notifiers")
+	}
+	if !errors.Is(err, errA) {
+		t.Fatalf("expected joined error to contain errA, got %v", err)
+	}
+	if !errors.Is(err, errB) {
+		t.Fatalf("expected joined error to contain errB, got %v", err)
+	}
+
+	for name, n := range map[string]*fakeNotifier{"a": a, "b": b, "c": c} {
+		if atomic.LoadInt32(&n.calls) != 1 {
+			t.Fatalf("expected notifier %s to be invoked exactly once, got %d", name, n.calls)
+		}
+	}
+}
+
+func TestMultiNotifierSendAllSucceed(t *testing.T) {
+	a := &fakeNotifier{}
+	b := &fakeNotifier{}
+
+	m := NewMultiNotifier(a, b)
+
+	if err := m.Send(context.Background(), TextType("hi")); err != nil {
+		t.Fatalf("expected no error when every notifier succeeds, got %v", err)
+	}
+}
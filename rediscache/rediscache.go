@@ -0,0 +1,35 @@
+// Package rediscache implements notify.Cache backed by Redis, letting a
+// provider's dedup window (e.g. dingtalk.WithDedupCache) survive past a
+// single process
+package rediscache
+
+import (
+	"context"
+	"github.com/ShyunnY/notify"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// Cache is a notify.Cache backed by Redis string keys with native TTLs
+type Cache struct {
+	client *redis.Client
+	prefix string
+}
+
+// New builds a Cache storing dedup keys as prefix+key in client
+func New(client *redis.Client, prefix string) *Cache {
+	return &Cache{client: client, prefix: prefix}
+}
+
+// Get reports whether key is present and not yet expired
+func (c *Cache) Get(key string) bool {
+	n, err := c.client.Exists(context.Background(), c.prefix+key).Result()
+	return err == nil && n > 0
+}
+
+// Set inserts key, relying on Redis's own expiry to evict it after ttl
+func (c *Cache) Set(key string, ttl time.Duration) error {
+	return c.client.Set(context.Background(), c.prefix+key, 1, ttl).Err()
+}
+
+var _ notify.Cache = (*Cache)(nil)
@@ -0,0 +1,115 @@
+// Package slack implements notify.Notifier for Slack incoming webhooks
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ShyunnY/notify"
+	"github.com/bytedance/sonic"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Bot is a Slack incoming webhook. It implements notify.Notifier.
+type Bot struct {
+	WebhookURL string
+
+	httpClient *http.Client
+}
+
+// NewBot builds a Slack bot posting to webhookURL
+func NewBot(webhookURL string) *Bot {
+	return &Bot{
+		WebhookURL: webhookURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// Send implements notify.Notifier, translating msg into Slack's native
+// payload shape before posting it to the incoming webhook. Slack webhooks
+// have no mention API of their own, so opts is only honored for isAtAll via
+// the @channel handle; per-user mentions need a Slack user ID, which the
+// common notify.AtOption set does not carry.
+func (b *Bot) Send(ctx context.Context, msg notify.Message, opts ...notify.AtOption) error {
+
+	people := new(notify.AtPeople)
+	for _, opt := range opts {
+		opt(people)
+	}
+
+	payload := map[string]interface{}{
+		"text": toText(msg, people),
+	}
+
+	reqData, err := sonic.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.WebhookURL, bytes.NewReader(reqData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack: webhook returned %d: %s", resp.StatusCode, string(respData))
+	}
+
+	return nil
+}
+
+// toText flattens a notify.Message down to Slack's plain "text" field,
+// since a basic incoming webhook has no structured card equivalent
+func toText(msg notify.Message, people *notify.AtPeople) string {
+	var text string
+
+	switch msg.Kind {
+	case notify.KindMarkdown:
+		text = fmt.Sprintf("*%s*\n%s", msg.Markdown.Title, msg.Markdown.Text)
+
+	case notify.KindLink:
+		text = fmt.Sprintf("*%s*\n%s\n%s", msg.Link.Title, msg.Link.Text, msg.Link.MessageURL)
+
+	case notify.KindActionCard:
+		lines := []string{fmt.Sprintf("*%s*", msg.ActionCard.Title), msg.ActionCard.Text}
+		for _, btn := range msg.ActionCard.Btns {
+			lines = append(lines, fmt.Sprintf("<%s|%s>", btn.ActionURL, btn.Title))
+		}
+		text = strings.Join(lines, "\n")
+
+	case notify.KindFeedCard:
+		lines := make([]string, 0, len(msg.FeedCard.Links))
+		for _, link := range msg.FeedCard.Links {
+			lines = append(lines, fmt.Sprintf("<%s|%s>", link.MessageURL, link.Title))
+		}
+		text = strings.Join(lines, "\n")
+
+	default: // notify.KindText and anything unrecognized fall back to plain text
+		if msg.Text != nil {
+			text = msg.Text.Content
+		}
+	}
+
+	if people.IsAtAll {
+		text += " <!channel>"
+	}
+
+	return text
+}
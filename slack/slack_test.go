@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"github.com/ShyunnY/notify"
+	"testing"
+)
+
+func TestToTextMessageKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    notify.Message
+		people *notify.AtPeople
+		want   string
+	}{
+		{
+			name:   "text",
+			msg:    notify.TextType("hello"),
+			people: &notify.AtPeople{},
+			want:   "hello",
+		},
+		{
+			name:   "text with at all",
+			msg:    notify.TextType("hello"),
+			people: &notify.AtPeople{IsAtAll: true},
+			want:   "hello <!channel>",
+		},
+		{
+			name:   "markdown",
+			msg:    notify.MarkDownType("title", "body"),
+			people: &notify.AtPeople{},
+			want:   "*title*\nbody",
+		},
+		{
+			name:   "link",
+			msg:    notify.LinkType("title", "text", "https://example.com", "https://example.com/pic"),
+			people: &notify.AtPeople{},
+			want:   "*title*\ntext\nhttps://example.com",
+		},
+		{
+			name: "actionCard",
+			msg: notify.ActionCardType("title", "text", "",
+				notify.ActionButton{Title: "open", ActionURL: "https://example.com/open"}),
+			people: &notify.AtPeople{},
+			want:   "*title*\ntext\n<https://example.com/open|open>",
+		},
+		{
+			name:   "feedCard",
+			msg:    notify.FeedCardType(notify.FeedLink{Title: "one", MessageURL: "https://example.com/1"}),
+			people: &notify.AtPeople{},
+			want:   "<https://example.com/1|one>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toText(tt.msg, tt.people)
+			if got != tt.want {
+				t.Fatalf("toText mismatch\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
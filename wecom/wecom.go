@@ -0,0 +1,162 @@
+// Package wecom implements notify.Notifier for WeCom (企业微信) group robots
+package wecom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ShyunnY/notify"
+	"github.com/bytedance/sonic"
+	"io"
+	"net/http"
+)
+
+// Bot is a WeCom group robot. It implements notify.Notifier.
+type Bot struct {
+	WebhookURL string
+
+	httpClient *http.Client
+}
+
+// NewBot builds a WeCom bot posting to webhookURL
+func NewBot(webhookURL string) *Bot {
+	return &Bot{
+		WebhookURL: webhookURL,
+		httpClient: &http.Client{},
+	}
+}
+
+// Send implements notify.Notifier, translating msg into WeCom's native
+// payload shape before posting it to the bot webhook
+func (b *Bot) Send(ctx context.Context, msg notify.Message, opts ...notify.AtOption) error {
+
+	people := new(notify.AtPeople)
+	for _, opt := range opts {
+		opt(people)
+	}
+
+	payload, err := toPayload(msg, people)
+	if err != nil {
+		return err
+	}
+
+	reqData, err := sonic.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.WebhookURL, bytes.NewReader(reqData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	result := new(Response)
+	if err := sonic.Unmarshal(respData, result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		return result
+	}
+
+	return nil
+}
+
+// toPayload translates a notify.Message into WeCom's native payload. Link
+// and feedCard messages both map to WeCom's "news" article list, and
+// actionCard maps to "textcard" since WeCom has no multi-button card type.
+func toPayload(msg notify.Message, people *notify.AtPeople) (map[string]interface{}, error) {
+	switch msg.Kind {
+	case notify.KindText:
+		return map[string]interface{}{
+			"msgtype": "text",
+			"text": map[string]interface{}{
+				"content":               msg.Text.Content,
+				"mentioned_list":        people.AtUserIds,
+				"mentioned_mobile_list": mentionedMobileList(people),
+			},
+		}, nil
+
+	case notify.KindMarkdown:
+		return map[string]interface{}{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"content": fmt.Sprintf("**%s**\n%s", msg.Markdown.Title, msg.Markdown.Text)},
+		}, nil
+
+	case notify.KindLink:
+		return map[string]interface{}{
+			"msgtype": "news",
+			"news": map[string]interface{}{
+				"articles": []map[string]string{{
+					"title":       msg.Link.Title,
+					"description": msg.Link.Text,
+					"url":         msg.Link.MessageURL,
+					"picurl":      msg.Link.PicURL,
+				}},
+			},
+		}, nil
+
+	case notify.KindActionCard:
+		url := ""
+		if len(msg.ActionCard.Btns) > 0 {
+			url = msg.ActionCard.Btns[0].ActionURL
+		}
+		return map[string]interface{}{
+			"msgtype": "textcard",
+			"textcard": map[string]string{
+				"title":       msg.ActionCard.Title,
+				"description": msg.ActionCard.Text,
+				"url":         url,
+			},
+		}, nil
+
+	case notify.KindFeedCard:
+		articles := make([]map[string]string, 0, len(msg.FeedCard.Links))
+		for _, link := range msg.FeedCard.Links {
+			articles = append(articles, map[string]string{
+				"title":  link.Title,
+				"url":    link.MessageURL,
+				"picurl": link.PicURL,
+			})
+		}
+		return map[string]interface{}{
+			"msgtype": "news",
+			"news":    map[string]interface{}{"articles": articles},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("wecom: unsupported message kind %q", msg.Kind)
+	}
+}
+
+// mentionedMobileList renders isAtAll as WeCom's "@all" sentinel mobile entry
+func mentionedMobileList(people *notify.AtPeople) []string {
+	if people.IsAtAll {
+		return append(append([]string{}, people.AtMobiles...), "@all")
+	}
+	return people.AtMobiles
+}
+
+// Response is the JSON body WeCom returns for every bot send call
+type Response struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (r Response) Error() string {
+	return fmt.Sprintf("wecom response info: errcode=%d,errmsg=%s", r.ErrCode, r.ErrMsg)
+}
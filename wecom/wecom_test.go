@@ -0,0 +1,103 @@
+package wecom
+
+import (
+	"github.com/ShyunnY/notify"
+	"reflect"
+	"testing"
+)
+
+func TestToPayloadMessageKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    notify.Message
+		people *notify.AtPeople
+		want   map[string]interface{}
+	}{
+		{
+			name:   "text",
+			msg:    notify.TextType("hello"),
+			people: &notify.AtPeople{AtUserIds: []string{"u1"}},
+			want: map[string]interface{}{
+				"msgtype": "text",
+				"text": map[string]interface{}{
+					"content":               "hello",
+					"mentioned_list":        []string{"u1"},
+					"mentioned_mobile_list": []string(nil),
+				},
+			},
+		},
+		{
+			name:   "markdown",
+			msg:    notify.MarkDownType("title", "body"),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msgtype":  "markdown",
+				"markdown": map[string]string{"content": "**title**\nbody"},
+			},
+		},
+		{
+			name:   "link",
+			msg:    notify.LinkType("title", "text", "https://example.com", "https://example.com/pic"),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msgtype": "news",
+				"news": map[string]interface{}{
+					"articles": []map[string]string{{
+						"title":       "title",
+						"description": "text",
+						"url":         "https://example.com",
+						"picurl":      "https://example.com/pic",
+					}},
+				},
+			},
+		},
+		{
+			name: "actionCard",
+			msg: notify.ActionCardType("title", "text", "",
+				notify.ActionButton{Title: "open", ActionURL: "https://example.com/open"}),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msgtype": "textcard",
+				"textcard": map[string]string{
+					"title":       "title",
+					"description": "text",
+					"url":         "https://example.com/open",
+				},
+			},
+		},
+		{
+			name:   "feedCard",
+			msg:    notify.FeedCardType(notify.FeedLink{Title: "one", MessageURL: "https://example.com/1", PicURL: "https://example.com/1.png"}),
+			people: &notify.AtPeople{},
+			want: map[string]interface{}{
+				"msgtype": "news",
+				"news": map[string]interface{}{
+					"articles": []map[string]string{{
+						"title":  "one",
+						"url":    "https://example.com/1",
+						"picurl": "https://example.com/1.png",
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toPayload(tt.msg, tt.people)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("toPayload mismatch\ngot:  %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToPayloadUnsupportedKind(t *testing.T) {
+	_, err := toPayload(notify.Message{Kind: "unknown"}, &notify.AtPeople{})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported message kind")
+	}
+}